@@ -0,0 +1,103 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/briandowns/openweathermap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tvanriel/waybar-openweathermap-widget/internal/weather"
+)
+
+// alertsCmd prints any active government weather alerts (via OpenWeatherMap's
+// One Call API) as a Waybar custom-module JSON line.
+var alertsCmd = &cobra.Command{
+	Use:   "alerts [lat] [long] [key]",
+	Short: "Print active weather alerts as a Waybar custom-module JSON line",
+	Args:  cobra.MaximumNArgs(3),
+	Run:   runAlerts,
+}
+
+func runAlerts(cmd *cobra.Command, args []string) {
+	setPositionalOverrides(args)
+	apikey := requireAPIKey()
+
+	request, err := openweathermap.NewOneCall(
+		strings.ToUpper(viper.GetString("units")),
+		viper.GetString("lang"),
+		apikey,
+		// Exclude everything but alerts; "alerts" itself must stay out of
+		// this list or the API drops the one block we're here for.
+		[]string{"current", "minutely", "hourly", "daily"},
+	)
+	if err != nil {
+		log.Fatalf("get one call client: %v", err)
+	}
+
+	err = request.OneCallByCoordinates(
+		&openweathermap.Coordinates{
+			Longitude: viper.GetFloat64("long"),
+			Latitude:  viper.GetFloat64("lat"),
+		},
+	)
+	if err != nil {
+		log.Fatalf("get alerts: %v", err)
+	}
+
+	timefmt := viper.GetString("timefmt")
+	data := &weather.Result{Class: "weather-alert-none"}
+
+	if len(request.Alerts) == 0 {
+		data.Text = "No alerts"
+		b, err := json.Marshal(data)
+		if err != nil {
+			log.Fatalf("encode json: %v", err)
+		}
+		os.Stdout.Write(b)
+		return
+	}
+
+	rows := make([]string, 0, len(request.Alerts))
+	for _, a := range request.Alerts {
+		rows = append(rows, strings.Join([]string{
+			a.Event,
+			" (", a.SenderName, ") ",
+			time.Unix(int64(a.Start), 0).Format(timefmt),
+			" - ",
+			time.Unix(int64(a.End), 0).Format(timefmt),
+			"\n",
+			a.Description,
+		}, ""))
+	}
+
+	data.Text = request.Alerts[0].Event
+	data.Tooltip = strings.Join(rows, "\n\n")
+	data.Class = "weather-alert"
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Fatalf("encode json: %v", err)
+	}
+	os.Stdout.Write(b)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/briandowns/openweathermap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tvanriel/waybar-openweathermap-widget/internal/weather"
+)
+
+// currentCmd prints the current conditions as a single Waybar JSON line.
+// It's also what rootCmd runs when invoked without a subcommand.
+var currentCmd = &cobra.Command{
+	Use:   "current [lat] [long] [key]",
+	Short: "Print the current weather as a Waybar custom-module JSON line",
+	Args:  cobra.MaximumNArgs(3),
+	Run:   runCurrent,
+}
+
+func runCurrent(cmd *cobra.Command, args []string) {
+	setPositionalOverrides(args)
+	apikey := requireAPIKey()
+
+	data, err := fetchCurrent(
+		apikey,
+		strings.ToUpper(viper.GetString("units")),
+		viper.GetString("lang"),
+		viper.GetFloat64("lat"),
+		viper.GetFloat64("long"),
+		viper.GetString("timefmt"),
+		resolveIconTheme(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Fatalf("encode json: %v", err)
+	}
+	os.Stdout.Write(b)
+}
+
+// fetchCurrent fetches current conditions and renders them into a Waybar
+// result. It's shared by `current` and `watch`, which need to fetch without
+// immediately terminating the process on error.
+func fetchCurrent(apikey, units, lang string, lat, long float64, timefmt string, theme weather.IconTheme) (*weather.Result, error) {
+	request, err := openweathermap.NewCurrent(units, lang, apikey)
+	if err != nil {
+		return nil, fmt.Errorf("get weatherinfo: %w", err)
+	}
+
+	err = request.CurrentByCoordinates(
+		&openweathermap.Coordinates{
+			Longitude: long,
+			Latitude:  lat,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get weather: %w", err)
+	}
+
+	icon := request.Weather[0].Icon
+	desc := weather.Description(request.Weather[0].ID)
+	sunrise := time.Unix(int64(request.Sys.Sunrise), 0).Format(timefmt)
+	sunset := time.Unix(int64(request.Sys.Sunset), 0).Format(timefmt)
+
+	return &weather.Result{
+		Text: weather.Text(theme.Icon(icon), strconv.FormatFloat(request.Main.Temp, 'f', 1, 64), units),
+		Tooltip: weather.Tooltip(
+			desc,
+			strconv.FormatInt(int64(request.Main.FeelsLike), 10),
+			strconv.FormatInt(int64(request.Main.Pressure), 10),
+			strconv.FormatInt(int64(request.Main.Humidity), 10),
+			sunrise,
+			sunset,
+			strconv.FormatFloat(request.Wind.Speed, 'f', 0, 64),
+			units,
+		),
+		Class: weather.Class(icon),
+	}, nil
+}
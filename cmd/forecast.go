@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/briandowns/openweathermap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tvanriel/waybar-openweathermap-widget/internal/weather"
+)
+
+var forecastPeriods int
+
+// forecastCmd prints the upcoming 5-day/3-hour forecast as a Waybar
+// custom-module JSON line, with one tooltip row per period.
+var forecastCmd = &cobra.Command{
+	Use:   "forecast [lat] [long] [key]",
+	Short: "Print the upcoming forecast as a Waybar custom-module JSON line",
+	Args:  cobra.MaximumNArgs(3),
+	Run:   runForecast,
+}
+
+func init() {
+	forecastCmd.Flags().IntVar(&forecastPeriods, "periods", 4, "number of upcoming 3-hour periods to show in the tooltip")
+}
+
+func runForecast(cmd *cobra.Command, args []string) {
+	setPositionalOverrides(args)
+	apikey := requireAPIKey()
+
+	if forecastPeriods < 1 {
+		log.Fatalf("--periods must be at least 1, got %d", forecastPeriods)
+	}
+
+	units := strings.ToUpper(viper.GetString("units"))
+	request, err := openweathermap.NewForecast("5", units, viper.GetString("lang"), apikey)
+	if err != nil {
+		log.Fatalf("get forecast client: %v", err)
+	}
+
+	err = request.DailyByCoordinates(
+		&openweathermap.Coordinates{
+			Longitude: viper.GetFloat64("long"),
+			Latitude:  viper.GetFloat64("lat"),
+		},
+		forecastPeriods,
+	)
+	if err != nil {
+		log.Fatalf("get forecast: %v", err)
+	}
+
+	fc, ok := request.ForecastWeatherJson.(*openweathermap.Forecast5WeatherData)
+	if !ok {
+		log.Fatalf("unexpected forecast payload type %T", request.ForecastWeatherJson)
+	}
+
+	periods := fc.List
+	if len(periods) > forecastPeriods {
+		periods = periods[:forecastPeriods]
+	}
+	if len(periods) == 0 {
+		log.Fatalf("forecast returned no periods")
+	}
+
+	timefmt := viper.GetString("timefmt")
+	theme := resolveIconTheme()
+	rows := make([]string, 0, len(periods))
+	for _, p := range periods {
+		icon := theme.Icon(p.Weather[0].Icon)
+		desc := weather.Description(p.Weather[0].ID)
+		when := time.Unix(int64(p.Dt), 0).Format(timefmt)
+		rows = append(rows, when+" "+weather.ForecastRow(
+			icon,
+			desc,
+			strconv.FormatInt(int64(p.Main.TempMax), 10),
+			strconv.FormatInt(int64(p.Main.TempMin), 10),
+			units,
+		))
+	}
+
+	next := periods[0]
+	data := &weather.Result{
+		Text: weather.Text(
+			theme.Icon(next.Weather[0].Icon),
+			strconv.FormatFloat(next.Main.Temp, 'f', 1, 64),
+			units,
+		),
+		Tooltip: strings.Join(rows, "\n"),
+		Class:   weather.Class(next.Weather[0].Icon),
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Fatalf("encode json: %v", err)
+	}
+	os.Stdout.Write(b)
+}
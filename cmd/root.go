@@ -17,50 +17,25 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
-	"strings"
-	"time"
-	"unicode"
 
-	"github.com/briandowns/openweathermap"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+
+	"github.com/tvanriel/waybar-openweathermap-widget/internal/weather"
 )
 
 var cfgFile string
-var (
-	icons = map[string]string{
-		"01d": "☀️",
-		"02d": "⛅️",
-		"03d": "☁️",
-		"04d": "☁️",
-		"09d": "🌧️",
-		"10d": "🌦️",
-		"11d": "⛈️",
-		"13d": "🌨️",
-		"50d": "🌫",
-
-		"01n": "☀️",
-		"02n": "⛅️",
-		"03n": "☁️",
-		"04n": "☁️",
-		"09n": "🌧️",
-		"10n": "🌦️",
-		"11n": "⛈️",
-		"13n": "🌨️",
-		"50n": "🌫",
-	}
 
-	timefmt = "15:04 MST"
-)
+// envPrefix namespaces the bound config keys as environment variables, e.g.
+// "apikey" is read from OWM_APIKEY so it doesn't leak onto the command line.
+const envPrefix = "OWM"
 
-// rootCmd represents the base command when called without any subcommands
+// rootCmd represents the base command when called without any subcommands.
+// With no subcommand given it behaves exactly like `current`.
 var rootCmd = &cobra.Command{
 	Use:   "waybar-openweathermap [lat] [long] [key]",
 	Short: "A brief description of your application",
@@ -70,74 +45,8 @@ examples and usage of using your application. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Args: cobra.ExactArgs(3),
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	Run: func(cmd *cobra.Command, args []string) {
-		long, err := strconv.ParseFloat(args[0], 64)
-		if err != nil {
-			log.Fatalf("parse longitude %s: %v", args[0], err)
-		}
-		
-                lat, err := strconv.ParseFloat(args[1], 64)
-		if err != nil {
-			log.Fatalf("parse latitude %s: %v", args[1], err)
-		}
-
-		request, err := openweathermap.NewCurrent(
-                        "C", 
-                        "en", 
-                        args[2], 
-                )
-		
-                if err != nil {
-			log.Fatalf("get weatherinfo: %v", err)
-                        return
-		}
-
-		err = request.CurrentByCoordinates(
-			&openweathermap.Coordinates{
-				Longitude: long,
-				Latitude:  lat,
-			},
-		)
-
-                if err != nil {
-                        log.Fatalf("get weather: %v", err)
-                        return
-                }
-
-		temp := request.Main.Temp
-		icon := icons[request.Weather[0].Icon]
-		desc := description(request.Weather[0].ID)
-
-		feelsLike := request.Main.FeelsLike
-		humidity := request.Main.Humidity
-		pressure := request.Main.Pressure
-		sunrise := time.Unix(int64(request.Sys.Sunrise), 0).Format(timefmt)
-		sunset := time.Unix(int64(request.Sys.Sunset), 0).Format(timefmt)
-		windSpeed := request.Wind.Speed
-
-		data := &result{
-			Text: text(icon, strconv.FormatFloat(temp, 'f', 1, 64)),
-			Tooltip: tooltip(
-                                desc,
-				strconv.FormatInt(int64(feelsLike), 10),
-				strconv.FormatInt(int64(pressure), 10),
-				strconv.FormatInt(int64(humidity), 10),
-				sunrise,
-				sunset,
-				strconv.FormatFloat(windSpeed, 'f', 0, 64),
-			),
-                        Class: "weather",
-		}
-
-		b, err := json.Marshal(data)
-		if err != nil {
-			log.Fatalf("encode json: %v", err)
-		}
-		os.Stdout.Write(b)
-	},
+	Args: cobra.MaximumNArgs(3),
+	Run:  runCurrent,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -158,9 +67,22 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.waybar-openweathermap.yaml)")
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().Float64("lat", 0, "latitude")
+	rootCmd.PersistentFlags().Float64("long", 0, "longitude")
+	rootCmd.PersistentFlags().String("apikey", "", "OpenWeatherMap API key")
+	rootCmd.PersistentFlags().String("units", "C", "units: C (Celsius), F (Fahrenheit), or K (Kelvin)")
+	rootCmd.PersistentFlags().String("lang", "en", "language for weather descriptions")
+	rootCmd.PersistentFlags().String("timefmt", weather.DefaultTimefmt, "time format used for sunrise/sunset")
+	rootCmd.PersistentFlags().String("icons", weather.EmojiTheme.Name(), "icon theme: emoji, nerdfont, or material")
+
+	for _, name := range []string{"lat", "long", "apikey", "units", "lang", "timefmt", "icons"} {
+		cobra.CheckErr(viper.BindPFlag(name, rootCmd.PersistentFlags().Lookup(name)))
+	}
+
+	rootCmd.AddCommand(currentCmd)
+	rootCmd.AddCommand(forecastCmd)
+	rootCmd.AddCommand(alertsCmd)
+	rootCmd.AddCommand(watchCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -179,6 +101,14 @@ func initConfig() {
 		viper.SetConfigName(".waybar-openweathermap")
 	}
 
+	viper.SetDefault("units", "C")
+	viper.SetDefault("lang", "en")
+	viper.SetDefault("timefmt", weather.DefaultTimefmt)
+	viper.SetDefault("icons", weather.EmojiTheme.Name())
+
+	// Env vars are prefixed (OWM_LAT, OWM_LONG, OWM_APIKEY, ...) so they sit
+	// between flags and the config file in viper's precedence order.
+	viper.SetEnvPrefix(envPrefix)
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
@@ -187,64 +117,51 @@ func initConfig() {
 	}
 }
 
-func text(icon, temp string) string {
-	return strings.Join([]string{
-		icon,
-                " ",
-		temp,
-		" °C",
-	}, "")
-}
-func tooltip(desc, feelsLike, pressure, humidity, sunrise, sunset, windspeed string) string {
-        caser := cases.Title(language.German)
-
-	return strings.TrimLeftFunc(strings.Join([]string{
-                caser.String(desc),
-                "\n",
-		"Feels like ",
-		feelsLike,
-		" °C\n",
-		"Pressure ",
-		pressure,
-		" hPa\n",
-		"Humidity ",
-		humidity,
-		"%\n",
-		"Sunrise ",
-		sunrise,
-		"\n",
-		"Sunset ",
-		sunset,
-		"\n",
-		"Wind speed ",
-		windspeed,
-		" m/sec",
-	}, ""), unicode.IsSpace)
+// requireAPIKey resolves the configured API key or exits with a helpful
+// error, since every subcommand needs one to talk to OpenWeatherMap.
+func requireAPIKey() string {
+	apikey := viper.GetString("apikey")
+	if apikey == "" {
+		fmt.Fprintf(os.Stderr, "no API key configured: set --apikey, %s_APIKEY, or apikey in the config file\n", envPrefix)
+		os.Exit(1)
+	}
+	return apikey
 }
 
-type result struct {
-	Text    string `json:"text"`
-	Tooltip string `json:"tooltip"`
-	Class   string `json:"class"`
+// resolveIconTheme builds the configured icon theme: the built-in theme
+// named by --icons/icons:, with any icon_overrides entries from the config
+// file layered on top of individual codes. icon_overrides is a sibling key
+// to icons rather than a nested icons.overrides, since icons is bound as a
+// scalar persistent flag and viper won't let a flag-bound leaf also hold a
+// map underneath it.
+func resolveIconTheme() weather.IconTheme {
+	name := viper.GetString("icons")
+	theme, ok := weather.Theme(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown icon theme %q, falling back to %q\n", name, weather.EmojiTheme.Name())
+		theme = weather.EmojiTheme
+	}
+	return theme.WithOverrides(viper.GetStringMapString("icon_overrides"))
 }
 
-
-func description(id int) (string) {
-        groups := [][]*openweathermap.ConditionData{
-                openweathermap.ThunderstormConditions,
-                openweathermap.DrizzleConditions,
-                openweathermap.RainConditions,
-                openweathermap.SnowConditions,
-                openweathermap.AtmosphereConditions,
-                openweathermap.CloudConditions,
-                openweathermap.AdditionalConditions,
-        }
-        for g := range groups {
-                for c := range groups[g] {
-                        if groups[g][c].ID == id {
-                                return groups[g][c].Meaning
-                        }
-                }
-        }
-        return ""
+// setPositionalOverrides lets lat/long/apikey still be given positionally,
+// taking precedence over whatever flags/env/config resolved to.
+func setPositionalOverrides(args []string) {
+	if len(args) > 0 {
+		lat, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			log.Fatalf("parse latitude %s: %v", args[0], err)
+		}
+		viper.Set("lat", lat)
+	}
+	if len(args) > 1 {
+		long, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			log.Fatalf("parse longitude %s: %v", args[1], err)
+		}
+		viper.Set("long", long)
+	}
+	if len(args) > 2 {
+		viper.Set("apikey", args[2])
+	}
 }
@@ -0,0 +1,44 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveIconThemeWithOverrides(t *testing.T) {
+	defer viper.Reset()
+
+	// icons (the theme name) and icon_overrides must be settable together in
+	// the same config file without one shadowing the other.
+	viper.Set("icons", "nerdfont")
+	viper.Set("icon_overrides", map[string]string{"01d": "X"})
+
+	theme := resolveIconTheme()
+
+	if theme.Name() != "nerdfont" {
+		t.Fatalf("theme name = %q, want %q", theme.Name(), "nerdfont")
+	}
+	if got := theme.Icon("01d"); got != "X" {
+		t.Fatalf("overridden icon for 01d = %q, want %q", got, "X")
+	}
+	if got, want := theme.Icon("11d"), "\uE30E"; got != want {
+		t.Fatalf("non-overridden nerdfont icon for 11d = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tvanriel/waybar-openweathermap-widget/internal/weather"
+)
+
+var watchInterval time.Duration
+
+// watchCmd runs as a long-lived Waybar custom-module process: it emits one
+// JSON line per fetch instead of exiting, so Waybar can poll it as a
+// subprocess rather than re-spawning `current` on every interval tick.
+var watchCmd = &cobra.Command{
+	Use:   "watch [lat] [long] [key]",
+	Short: "Stream current weather as Waybar JSON lines until killed",
+	Long: `watch fetches the current weather on a timer and prints one JSON
+line per fetch to stdout, as Waybar's long-running custom-module processes
+expect. Sending SIGUSR1 or SIGHUP triggers an immediate refetch, which lets
+a Waybar click action force a refresh without waiting for the next tick.
+
+If a fetch fails, the error is logged to stderr and the last known-good
+text is re-emitted with class "weather-error" instead of exiting, so a
+transient OpenWeatherMap outage doesn't blank the bar.`,
+	Args: cobra.MaximumNArgs(3),
+	Run:  runWatch,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Minute, "how often to refetch between fetches")
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	setPositionalOverrides(args)
+	apikey := requireAPIKey()
+
+	units := strings.ToUpper(viper.GetString("units"))
+	lang := viper.GetString("lang")
+	timefmt := viper.GetString("timefmt")
+	lat := viper.GetFloat64("lat")
+	long := viper.GetFloat64("long")
+	theme := resolveIconTheme()
+
+	refresh := make(chan os.Signal, 1)
+	signal.Notify(refresh, syscall.SIGUSR1, syscall.SIGHUP)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var lastGood *weather.Result
+	fetchAndEmit := func() {
+		data, err := fetchCurrent(apikey, units, lang, lat, long, timefmt, theme)
+		if err != nil {
+			log.Println("fetch weather:", err)
+			emitJSON(staleResult(lastGood, err))
+			return
+		}
+		lastGood = data
+		emitJSON(data)
+	}
+
+	fetchAndEmit()
+	for {
+		select {
+		case <-ticker.C:
+			fetchAndEmit()
+		case <-refresh:
+			fetchAndEmit()
+		}
+	}
+}
+
+// staleResult builds the fallback result emitted when a fetch fails: the
+// last known-good text/tooltip if we have one, otherwise the error itself,
+// always flagged with class "weather-error".
+func staleResult(lastGood *weather.Result, err error) *weather.Result {
+	if lastGood == nil {
+		return &weather.Result{Text: "?", Tooltip: err.Error(), Class: "weather-error"}
+	}
+	return &weather.Result{Text: lastGood.Text, Tooltip: lastGood.Tooltip, Class: "weather-error"}
+}
+
+// emitJSON writes one Waybar JSON line to stdout and flushes it immediately,
+// since Waybar reads this process's stdout line by line.
+func emitJSON(data *weather.Result) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Fatalf("encode json: %v", err)
+	}
+	os.Stdout.Write(append(b, '\n'))
+	_ = os.Stdout.Sync()
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package weather
+
+// IconTheme maps OWM icon codes, including their "d"/"n" day/night suffix
+// (e.g. "01d" vs "01n"), to the glyph printed on the bar.
+type IconTheme struct {
+	name  string
+	icons map[string]string
+}
+
+// Name is the theme's selectable name, e.g. "nerdfont".
+func (t IconTheme) Name() string { return t.name }
+
+// Icon returns the glyph configured for an OWM icon code such as "10d". It
+// returns "" if the theme has no entry for that code.
+func (t IconTheme) Icon(code string) string {
+	return t.icons[code]
+}
+
+// WithOverrides returns a copy of t with individual codes replaced by
+// overrides, as configured via icon_overrides in the config file.
+func (t IconTheme) WithOverrides(overrides map[string]string) IconTheme {
+	if len(overrides) == 0 {
+		return t
+	}
+	merged := make(map[string]string, len(t.icons)+len(overrides))
+	for code, icon := range t.icons {
+		merged[code] = icon
+	}
+	for code, icon := range overrides {
+		merged[code] = icon
+	}
+	return IconTheme{name: t.name, icons: merged}
+}
+
+// EmojiTheme is the default icon theme: plain emoji, distinguishing day and
+// night for clear, partly-cloudy and rain-shower conditions.
+var EmojiTheme = IconTheme{
+	name: "emoji",
+	icons: map[string]string{
+		"01d": "☀️", "01n": "🌙",
+		"02d": "⛅️", "02n": "☁️",
+		"03d": "☁️", "03n": "☁️",
+		"04d": "☁️", "04n": "☁️",
+		"09d": "🌧️", "09n": "🌧️",
+		"10d": "🌦️", "10n": "🌧️",
+		"11d": "⛈️", "11n": "⛈️",
+		"13d": "🌨️", "13n": "🌨️",
+		"50d": "🌫", "50n": "🌫",
+	},
+}
+
+// NerdFontTheme uses the Weather Icons codepoints bundled into Nerd Fonts
+// (the "nf-weather-*" glyphs), for Waybar setups using a patched font
+// instead of an emoji font.
+var NerdFontTheme = IconTheme{
+	name: "nerdfont",
+	icons: map[string]string{
+		"01d": "\uE30D", "01n": "\uE32B",
+		"02d": "\uE302", "02n": "\uE37E",
+		"03d": "\uE312", "03n": "\uE312",
+		"04d": "\uE312", "04n": "\uE312",
+		"09d": "\uE319", "09n": "\uE319",
+		"10d": "\uE308", "10n": "\uE325",
+		"11d": "\uE30E", "11n": "\uE30E",
+		"13d": "\uE30A", "13n": "\uE30A",
+		"50d": "\uE313", "50n": "\uE346",
+	},
+}
+
+// MaterialTheme uses Material Design Icons' "mdi-weather-*" glyphs.
+var MaterialTheme = IconTheme{
+	name: "material",
+	icons: map[string]string{
+		"01d": "\U000F0599", "01n": "\U000F0594",
+		"02d": "\U000F0595", "02n": "\U000F0F61",
+		"03d": "\U000F0590", "03n": "\U000F0590",
+		"04d": "\U000F0590", "04n": "\U000F0590",
+		"09d": "\U000F0596", "09n": "\U000F0596",
+		"10d": "\U000F0597", "10n": "\U000F0F33",
+		"11d": "\U000F067E", "11n": "\U000F067E",
+		"13d": "\U000F0598", "13n": "\U000F0598",
+		"50d": "\U000F0591", "50n": "\U000F0591",
+	},
+}
+
+// builtinThemes is keyed by the name users pass to --icons or set as
+// icons: in the config file.
+var builtinThemes = map[string]IconTheme{
+	EmojiTheme.name:    EmojiTheme,
+	NerdFontTheme.name: NerdFontTheme,
+	MaterialTheme.name: MaterialTheme,
+}
+
+// Theme looks up a built-in theme by name. ok is false for an unrecognized
+// name, in which case callers should fall back to EmojiTheme.
+func Theme(name string) (theme IconTheme, ok bool) {
+	theme, ok = builtinThemes[name]
+	return theme, ok
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2024 Ted van Riel
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package weather holds the Waybar-output formatting shared by the current,
+// forecast and alerts subcommands: icon lookup, condition descriptions and
+// the text/tooltip/class rendering that becomes the module's JSON line.
+package weather
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/briandowns/openweathermap"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// DefaultTimefmt is the time.Format layout used for sunrise/sunset unless
+// overridden.
+const DefaultTimefmt = "15:04 MST"
+
+// Result is the Waybar custom-module JSON payload: text is shown on the
+// bar, tooltip on hover, and class lets style.css target specific
+// conditions.
+type Result struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+// Text renders the bar label for a single reading: icon, temperature and
+// unit.
+func Text(icon, temp, units string) string {
+	return strings.Join([]string{
+		icon,
+		" ",
+		temp,
+		" °" + units,
+	}, "")
+}
+
+// Tooltip renders the multi-line hover text for a single current-weather
+// reading.
+func Tooltip(desc, feelsLike, pressure, humidity, sunrise, sunset, windspeed, units string) string {
+	caser := cases.Title(language.German)
+
+	return strings.TrimLeftFunc(strings.Join([]string{
+		caser.String(desc),
+		"\n",
+		"Feels like ",
+		feelsLike,
+		" °" + units + "\n",
+		"Pressure ",
+		pressure,
+		" hPa\n",
+		"Humidity ",
+		humidity,
+		"%\n",
+		"Sunrise ",
+		sunrise,
+		"\n",
+		"Sunset ",
+		sunset,
+		"\n",
+		"Wind speed ",
+		windspeed,
+		" m/sec",
+	}, ""), unicode.IsSpace)
+}
+
+// ForecastRow renders one tooltip line of a forecast: icon, description and
+// the hi/lo temperature for that period.
+func ForecastRow(icon, desc, hi, lo, units string) string {
+	return strings.Join([]string{icon, " ", hi, "°/", lo, "°" + units, " ", desc}, "")
+}
+
+// Description looks up the human-readable meaning of an OpenWeatherMap
+// condition ID across all of its condition groups.
+func Description(id int) string {
+	groups := [][]*openweathermap.ConditionData{
+		openweathermap.ThunderstormConditions,
+		openweathermap.DrizzleConditions,
+		openweathermap.RainConditions,
+		openweathermap.SnowConditions,
+		openweathermap.AtmosphereConditions,
+		openweathermap.CloudConditions,
+		openweathermap.AdditionalConditions,
+	}
+	for g := range groups {
+		for c := range groups[g] {
+			if groups[g][c].ID == id {
+				return groups[g][c].Meaning
+			}
+		}
+	}
+	return ""
+}
+
+// classByIconGroup maps an OWM icon code's two-digit group (the code minus
+// its d/n day/night suffix) to the Waybar CSS class users can style.
+var classByIconGroup = map[string]string{
+	"01": "weather-clear",
+	"02": "weather-clouds",
+	"03": "weather-clouds",
+	"04": "weather-clouds",
+	"09": "weather-rain",
+	"10": "weather-rain",
+	"11": "weather-thunderstorm",
+	"13": "weather-snow",
+	"50": "weather-fog",
+}
+
+// Class derives the Waybar "class" field from an OWM icon code, e.g. "10d"
+// becomes "weather-rain". Unknown codes fall back to "weather".
+func Class(icon string) string {
+	if len(icon) < 2 {
+		return "weather"
+	}
+	if class, ok := classByIconGroup[icon[:2]]; ok {
+		return class
+	}
+	return "weather"
+}